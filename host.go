@@ -0,0 +1,116 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Matcher is an additional predicate a route must satisfy beyond its HTTP
+// method and path, such as a required header, scheme, or query parameter.
+type Matcher interface {
+	Match(req *http.Request) bool
+}
+
+// MatcherFunc adapts a function to a Matcher.
+type MatcherFunc func(req *http.Request) bool
+
+// Match calls f(req).
+func (f MatcherFunc) Match(req *http.Request) bool {
+	return f(req)
+}
+
+// AddMatched inserts a route value like [Trie.Add], additionally requiring
+// req to satisfy every matcher for [Trie.Lookup] to return it. A route
+// whose matchers reject req is treated the same as a route that doesn't
+// exist: lookup falls through to a matching prefix route, if any, or else
+// reports not found.
+func (t *Trie[T]) AddMatched(method, pattern string, value T, matchers ...Matcher) {
+	t.addRoute(method, pattern, value, nil, matchers)
+}
+
+// AddHost registers a route value like [Trie.Add], additionally requiring
+// req's Host header to match host. A host label wrapped in braces, e.g. the
+// "sub" in "{sub}.example.com", is a parameter: it matches any label and is
+// returned under its name in [Trie.LookupHost]'s params, alongside any path
+// params.
+//
+// AddHost is for routes that must only match specific hosts, such as
+// multi-tenant subdomain dispatch; routes added with Add or AddMatched
+// match regardless of Host.
+func (t *Trie[T]) AddHost(host, method, pattern string, value T) {
+	if t.hosts == nil {
+		t.hosts = NewTrie[T]()
+		// The hosts trie matches a single synthetic path built from a
+		// host's dot-separated labels followed by the route's own path
+		// segments, so its ParamFunc must recognize both the "{name}" host
+		// label syntax and the ":name" path segment syntax.
+		t.hosts.ParamFunc = func(seg string) (name string, isParam bool) {
+			if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+				return seg[1 : len(seg)-1], true
+			}
+			if !strings.HasPrefix(seg, ":") {
+				return "", false
+			}
+			name = strings.TrimLeft(seg, ":")
+			if i := strings.IndexByte(name, '('); i != -1 {
+				name = name[:i]
+			}
+			return name, true
+		}
+	}
+	t.hosts.Add(method, hostRoutePath(host, pattern), value)
+}
+
+// LookupHost searches for the route value registered with [Trie.AddHost]
+// that matches req's Host header, method, and path. params holds both host
+// params and path params together. found is false if t has no host routes,
+// or none match.
+func (t *Trie[T]) LookupHost(req *http.Request) (value T, params map[string]string, found bool) {
+	if t.hosts == nil {
+		var zero T
+		return zero, nil, false
+	}
+	return t.hosts.Lookup(hostSyntheticRequest(req))
+}
+
+// hostSyntheticRequest returns a shallow copy of req whose URL.Path is the
+// synthetic host+path used internally by the hosts trie, so the hosts trie
+// can be searched with the same [Trie.Lookup]/lookupNode machinery as any
+// other route; see ServeHTTP and LookupHost.
+func hostSyntheticRequest(req *http.Request) *http.Request {
+	u := *req.URL
+	u.Path = hostRoutePath(hostWithoutPort(req.Host), req.URL.Path)
+
+	synthetic := *req
+	synthetic.URL = &u
+	return &synthetic
+}
+
+// hostPathSuffix returns the real URL path portion of a synthetic host+path
+// produced by the hosts trie (e.g. by [Trie.RedirectPath]), inverting
+// hostRoutePath's host-label prefix so ServeHTTP can redirect to an actual
+// path instead of the internal synthetic one.
+func hostPathSuffix(host, synthetic string) string {
+	labels := strings.Split(host, ".")
+	segs := pathSegments(synthetic)
+	if len(segs) <= len(labels) {
+		return "/"
+	}
+	return "/" + strings.Join(segs[len(labels):], "/")
+}
+
+// hostRoutePath joins a dot-separated host pattern and a path pattern into
+// the single slash-separated pattern used internally by the hosts trie, so
+// host labels are matched exactly like leading path segments.
+func hostRoutePath(host, pattern string) string {
+	labels := strings.Split(host, ".")
+	return "/" + strings.Join(labels, "/") + pattern
+}
+
+// hostWithoutPort strips a trailing ":port" from an HTTP Host header.
+func hostWithoutPort(host string) string {
+	if i := strings.LastIndexByte(host, ':'); i != -1 && i > strings.LastIndexByte(host, ']') {
+		return host[:i]
+	}
+	return host
+}
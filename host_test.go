@@ -0,0 +1,217 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nahojer/routes"
+)
+
+func TestTrie_AddHost(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.AddHost("{sub}.example.com", "GET", "/users/:id", "tenant-user")
+	rt.AddHost("admin.example.com", "GET", "/users/:id", "admin-user")
+
+	req := httptest.NewRequest("GET", "http://acme.example.com/users/42", nil)
+	req.Host = "acme.example.com"
+	gotValue, gotParams, found := rt.LookupHost(req)
+	if !found {
+		t.Fatal("expected a match for a tenant subdomain")
+	}
+	if gotValue != "tenant-user" {
+		t.Errorf("got value %q, want %q", gotValue, "tenant-user")
+	}
+	if gotParams["sub"] != "acme" || gotParams["id"] != "42" {
+		t.Errorf("got params %+v, want sub=acme id=42", gotParams)
+	}
+
+	req = httptest.NewRequest("GET", "http://admin.example.com/users/42", nil)
+	req.Host = "admin.example.com"
+	gotValue, _, found = rt.LookupHost(req)
+	if !found || gotValue != "admin-user" {
+		t.Errorf("got %q, %t; want %q, true", gotValue, found, "admin-user")
+	}
+}
+
+func TestTrie_AddHost_PortIsIgnored(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.AddHost("example.com", "GET", "/", "home")
+
+	req := httptest.NewRequest("GET", "http://example.com:8080/", nil)
+	req.Host = "example.com:8080"
+	gotValue, _, found := rt.LookupHost(req)
+	if !found || gotValue != "home" {
+		t.Errorf("got %q, %t; want %q, true", gotValue, found, "home")
+	}
+}
+
+func TestTrie_ServeHTTP_Host(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+	rt.AddHost("{sub}.example.com", "GET", "/dashboard", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, routes.Params(r)["sub"])
+	}))
+	rt.Add("GET", "/dashboard", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "host-agnostic")
+	}))
+
+	req := httptest.NewRequest("GET", "http://acme.example.com/dashboard", nil)
+	req.Host = "acme.example.com"
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Body.String() != "acme" {
+		t.Errorf("got body %q, want %q", w.Body.String(), "acme")
+	}
+
+	req = httptest.NewRequest("GET", "http://other.example.org/dashboard", nil)
+	req.Host = "other.example.org"
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Body.String() != "host-agnostic" {
+		t.Errorf("got body %q, want %q", w.Body.String(), "host-agnostic")
+	}
+}
+
+// A request whose host matches an AddHost route, but whose method doesn't,
+// must get a real 405 with an Allow header, the same promise ServeHTTP
+// makes for host-agnostic routes; it must not fall through to an unrelated
+// host-agnostic route registered at the same path.
+func TestTrie_ServeHTTP_Host_MethodNotAllowed(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+	rt.AddHost("tenant.example.com", "GET", "/dashboard", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "tenant-dashboard")
+	}))
+	rt.Add("POST", "/dashboard", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "host-agnostic-post")
+	}))
+
+	req := httptest.NewRequest("POST", "http://tenant.example.com/dashboard", nil)
+	req.Host = "tenant.example.com"
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+	if got, want := w.Header().Get("Allow"), "GET"; got != want {
+		t.Errorf("got Allow header %q, want %q", got, want)
+	}
+	if w.Body.String() == "host-agnostic-post" {
+		t.Error("must not fall through to an unrelated host-agnostic route")
+	}
+
+	req = httptest.NewRequest(http.MethodOptions, "http://tenant.example.com/dashboard", nil)
+	req.Host = "tenant.example.com"
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got, want := w.Header().Get("Allow"), "GET"; got != want {
+		t.Errorf("got Allow header %q, want %q", got, want)
+	}
+}
+
+// ServeHTTP applies t's RedirectFixedPath to a host route's own path, not
+// just the host-agnostic trie, and redirects to the real path -- not the
+// internal synthetic host+path used to search the hosts trie.
+func TestTrie_ServeHTTP_Host_RedirectFixedPath(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+	rt.RedirectFixedPath = true
+	rt.AddHost("tenant.example.com", "GET", "/users/list", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "http://tenant.example.com/admin/../users/list", nil)
+	req.Host = "tenant.example.com"
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMovedPermanently {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusMovedPermanently)
+	}
+	if got, want := w.Header().Get("Location"), "/users/list"; got != want {
+		t.Errorf("got Location %q, want %q", got, want)
+	}
+}
+
+func TestTrie_LookupHost_NoHostRoutes(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Add("GET", "/", "home")
+
+	req := httptest.NewRequest("GET", "http://example.com/", nil)
+	if _, _, found := rt.LookupHost(req); found {
+		t.Error("expected no match when no routes were registered with AddHost")
+	}
+}
+
+func TestTrie_AddMatched(t *testing.T) {
+	rt := routes.NewTrie[string]()
+
+	requireJSON := routes.MatcherFunc(func(req *http.Request) bool {
+		return req.Header.Get("Accept") == "application/json"
+	})
+	rt.AddMatched("GET", "/users", "json", requireJSON)
+
+	req := httptest.NewRequest("GET", "http://localhost/users", nil)
+	req.Header.Set("Accept", "application/json")
+	gotValue, _, found := rt.Lookup(req)
+	if !found || gotValue != "json" {
+		t.Errorf("got %q, %t; want %q, true", gotValue, found, "json")
+	}
+
+	req = httptest.NewRequest("GET", "http://localhost/users", nil)
+	if _, _, found := rt.Lookup(req); found {
+		t.Error("expected no match when the matcher rejects the request")
+	}
+}
+
+func TestTrie_AddMatched_FallsBackWhenMatcherRejects(t *testing.T) {
+	rt := routes.NewTrie[string]()
+
+	never := routes.MatcherFunc(func(req *http.Request) bool { return false })
+	rt.Add("GET", "/reports/...", "reports-index")
+	rt.AddMatched("GET", "/reports/secret", "secret-report", never)
+
+	req := httptest.NewRequest("GET", "http://localhost/reports/secret", nil)
+	gotValue, _, found := rt.Lookup(req)
+	if !found || gotValue != "reports-index" {
+		t.Errorf("got %q, %t; want fallback to %q", gotValue, found, "reports-index")
+	}
+}
+
+// A method registered only through AddMatched must not appear in
+// AllowedMethods for a request its matcher rejects: a real request with
+// that method would fall through to not-found, so advertising it in an
+// Allow header (and answering OPTIONS with it) would be a lie.
+func TestTrie_AllowedMethods_ExcludesRejectedMatcher(t *testing.T) {
+	rt := routes.NewTrie[string]()
+
+	requireJSON := routes.MatcherFunc(func(req *http.Request) bool {
+		return req.Header.Get("Accept") == "application/json"
+	})
+	rt.Add("GET", "/widgets", "get")
+	rt.AddMatched("POST", "/widgets", "post-json", requireJSON)
+
+	req := httptest.NewRequest("DELETE", "http://localhost/widgets", nil)
+	methods, ok := rt.AllowedMethods(req)
+	if !ok {
+		t.Fatal("expected AllowedMethods to report the path exists")
+	}
+	if len(methods) != 1 || methods[0] != "GET" {
+		t.Errorf("got methods %v, want [GET] (POST's matcher should reject this request)", methods)
+	}
+
+	req = httptest.NewRequest("DELETE", "http://localhost/widgets", nil)
+	req.Header.Set("Accept", "application/json")
+	methods, ok = rt.AllowedMethods(req)
+	if !ok {
+		t.Fatal("expected AllowedMethods to report the path exists")
+	}
+	want := []string{"GET", "POST"}
+	if len(methods) != len(want) || methods[0] != want[0] || methods[1] != want[1] {
+		t.Errorf("got methods %v, want %v", methods, want)
+	}
+}
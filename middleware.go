@@ -0,0 +1,182 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Middleware wraps an http.Handler with additional behavior, such as
+// logging, authentication, or panic recovery.
+type Middleware func(http.Handler) http.Handler
+
+// Use appends global middleware to t. Global middleware wraps every route
+// in t, including routes added directly with [Trie.Add] and routes added
+// through a [Group], in registration order: the first middleware passed to
+// Use is the outermost wrapper. Use is only meaningful when T is
+// http.Handler; see ServeHTTP.
+func (t *Trie[T]) Use(mws ...Middleware) {
+	t.mws = append(t.mws, mws...)
+}
+
+// Group represents a set of routes sharing a common path prefix and
+// middleware stack. Groups are created with [Trie.Group] and may be nested
+// with [Group.Group].
+type Group[T any] struct {
+	t      *Trie[T]
+	prefix string
+	mws    []Middleware
+}
+
+// Group returns a new Group rooted at prefix. Routes added to the group are
+// wrapped with mws, innermost to the handler, in addition to any global
+// middleware registered with [Trie.Use].
+func (t *Trie[T]) Group(prefix string, mws ...Middleware) *Group[T] {
+	return &Group[T]{
+		t:      t,
+		prefix: strings.TrimRight(prefix, "/"),
+		mws:    mws,
+	}
+}
+
+// Group returns a nested Group whose prefix is g's prefix joined with
+// prefix, and whose middleware stack extends g's with mws.
+func (g *Group[T]) Group(prefix string, mws ...Middleware) *Group[T] {
+	stack := make([]Middleware, 0, len(g.mws)+len(mws))
+	stack = append(stack, g.mws...)
+	stack = append(stack, mws...)
+
+	return &Group[T]{
+		t:      g.t,
+		prefix: g.prefix + strings.TrimRight(prefix, "/"),
+		mws:    stack,
+	}
+}
+
+// Add inserts a route value like [Trie.Add], joining pattern onto the
+// group's prefix and wrapping the route with the group's middleware stack.
+func (g *Group[T]) Add(method, pattern string, value T) {
+	g.t.addRoute(method, g.prefix+pattern, value, g.mws, nil)
+}
+
+// paramsCtxKey is the context key under which ServeHTTP stores the matched
+// route's params.
+type paramsCtxKey struct{}
+
+// Params returns the route params stored in req's context by ServeHTTP. It
+// returns nil if req was not served through ServeHTTP or no route matched.
+func Params(req *http.Request) map[string]string {
+	params, _ := req.Context().Value(paramsCtxKey{}).(map[string]string)
+	return params
+}
+
+// ServeHTTP implements http.Handler, making t usable as a router on its
+// own. If req's Host header matches a route added with [Trie.AddHost], that
+// route is tried first, with its own redirect and method-not-allowed
+// handling scoped to routes registered for that host (see below); otherwise
+// ServeHTTP looks up the value matching req's method and path. If the
+// matched value is an http.Handler, ServeHTTP invokes it after wrapping it
+// with t's global middleware (see [Trie.Use]) as the outermost layers and
+// its route's middleware (see [Group]) inside them. Handlers can retrieve the matched route's
+// params, host or path alike, with Params.
+//
+// If req's path matches a registered route but not its method, ServeHTTP
+// responds with http.StatusMethodNotAllowed and an Allow header listing the
+// methods that would match. OPTIONS requests without a registered OPTIONS
+// handler are answered automatically with the same Allow header and
+// http.StatusNoContent. If [Trie.RedirectTrailingSlash] or
+// [Trie.RedirectFixedPath] are enabled and find an alternate path for an
+// otherwise-unmatched request, ServeHTTP redirects to it (301 for GET/HEAD,
+// 308 otherwise, to preserve the request method and body). If no route
+// matches the path at all, or the matched value is not an http.Handler,
+// ServeHTTP responds with http.NotFound. ServeHTTP is only meaningful when T
+// is http.Handler.
+func (t *Trie[T]) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if t.hosts != nil {
+		hostReq := hostSyntheticRequest(req)
+
+		if n, method, params, found := t.hosts.lookupNode(hostReq); found {
+			if !t.serveNode(w, req, n, method, params) {
+				http.NotFound(w, req)
+			}
+			return
+		}
+
+		if path, ok := t.hosts.redirectPath(hostReq, t.RedirectTrailingSlash, t.RedirectFixedPath); ok {
+			t.redirect(w, req, hostPathSuffix(hostWithoutPort(req.Host), path))
+			return
+		}
+
+		// AllowedMethods reporting ok here means req's host and path do
+		// match a route registered with AddHost, just not its method: this
+		// request must not fall through to an unrelated host-agnostic
+		// route below.
+		if methods, ok := t.hosts.AllowedMethods(hostReq); ok {
+			writeMethodNotAllowed(w, req, methods)
+			return
+		}
+	}
+
+	if n, method, params, found := t.lookupNode(req); found {
+		if !t.serveNode(w, req, n, method, params) {
+			http.NotFound(w, req)
+		}
+		return
+	}
+
+	if path, ok := t.RedirectPath(req); ok {
+		t.redirect(w, req, path)
+		return
+	}
+
+	methods, hasPath := t.AllowedMethods(req)
+	if !hasPath {
+		http.NotFound(w, req)
+		return
+	}
+
+	writeMethodNotAllowed(w, req, methods)
+}
+
+// redirect issues a redirect to path, 301 for GET/HEAD or 308 otherwise, to
+// preserve req's method and body.
+func (t *Trie[T]) redirect(w http.ResponseWriter, req *http.Request, path string) {
+	status := http.StatusMovedPermanently
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		status = http.StatusPermanentRedirect
+	}
+	http.Redirect(w, req, path, status)
+}
+
+// writeMethodNotAllowed sets the Allow header to methods and responds with
+// http.StatusNoContent for an OPTIONS request, or
+// http.StatusMethodNotAllowed otherwise.
+func writeMethodNotAllowed(w http.ResponseWriter, req *http.Request, methods []string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	if req.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// serveNode serves n's value for method through req and w, if the value is
+// an http.Handler, wrapping it with t's global middleware (outermost) and
+// n's route middleware (inside it) and storing params for Params. It reports
+// whether the value was an http.Handler at all; ServeHTTP treats false the
+// same as no match.
+func (t *Trie[T]) serveNode(w http.ResponseWriter, req *http.Request, n *node[T], method string, params map[string]string) bool {
+	h, ok := any(n.values[method]).(http.Handler)
+	if !ok {
+		return false
+	}
+
+	mws := append(append([]Middleware{}, t.mws...), n.middlewares[method]...)
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+
+	ctx := context.WithValue(req.Context(), paramsCtxKey{}, params)
+	h.ServeHTTP(w, req.WithContext(ctx))
+	return true
+}
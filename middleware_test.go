@@ -0,0 +1,115 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nahojer/routes"
+)
+
+func TestTrie_ServeHTTP_Middleware(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+
+	var order []string
+	mw := func(name string) routes.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	rt.Use(mw("global"))
+
+	api := rt.Group("/api", mw("group"))
+	api.Add("GET", "/users/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+		fmt.Fprint(w, routes.Params(r)["id"])
+	}))
+
+	req := httptest.NewRequest("GET", "http://localhost/api/users/42", nil)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	wantOrder := []string{"global", "group", "handler"}
+	if len(order) != len(wantOrder) {
+		t.Fatalf("got order %v, want %v", order, wantOrder)
+	}
+	for i := range wantOrder {
+		if order[i] != wantOrder[i] {
+			t.Errorf("got order %v, want %v", order, wantOrder)
+			break
+		}
+	}
+
+	if w.Body.String() != "42" {
+		t.Errorf("got body %q, want %q", w.Body.String(), "42")
+	}
+}
+
+func TestTrie_ServeHTTP_NestedGroup(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+
+	v1 := rt.Group("/v1")
+	admin := v1.Group("/admin")
+	admin.Add("GET", "/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "pong")
+	}))
+
+	req := httptest.NewRequest("GET", "http://localhost/v1/admin/ping", nil)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Body.String() != "pong" {
+		t.Errorf("got body %q, want %q", w.Body.String(), "pong")
+	}
+}
+
+func TestTrie_ServeHTTP_MethodNotAllowed(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+	rt.Add("GET", "/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rt.Add("POST", "/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("DELETE", "http://localhost/widgets", nil)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+	if got, want := w.Header().Get("Allow"), "GET, POST"; got != want {
+		t.Errorf("got Allow header %q, want %q", got, want)
+	}
+}
+
+func TestTrie_ServeHTTP_Options(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+	rt.Add("GET", "/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rt.Add("POST", "/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodOptions, "http://localhost/widgets", nil)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got, want := w.Header().Get("Allow"), "GET, POST"; got != want {
+		t.Errorf("got Allow header %q, want %q", got, want)
+	}
+}
+
+func TestTrie_ServeHTTP_NotFound(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+
+	req := httptest.NewRequest("GET", "http://localhost/missing", nil)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,175 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RedirectPath reports an alternate, redirect-worthy path for req when its
+// exact path does not match any route, but [Trie.RedirectTrailingSlash]
+// and/or [Trie.RedirectFixedPath] are enabled and find a path that does.
+// ok is false when req's path already matches a route, or neither flag is
+// set, or no alternate path matches. Callers (including [Trie.ServeHTTP])
+// should issue a 301/308 redirect to the returned path.
+func (t *Trie[T]) RedirectPath(req *http.Request) (path string, ok bool) {
+	return t.redirectPath(req, t.RedirectTrailingSlash, t.RedirectFixedPath)
+}
+
+// redirectPath is RedirectPath's implementation, taking the trailing-slash
+// and fixed-path flags explicitly so ServeHTTP can apply t's flags while
+// searching t.hosts, a distinct Trie whose own flags are never set via
+// AddHost.
+func (t *Trie[T]) redirectPath(req *http.Request, trailingSlash, fixedPath bool) (path string, ok bool) {
+	if !trailingSlash && !fixedPath {
+		return "", false
+	}
+
+	if _, _, _, found := t.lookupNode(req); found {
+		return "", false
+	}
+
+	orig := req.URL.Path
+
+	if trailingSlash {
+		if alt := toggleTrailingSlash(orig); alt != orig && t.pathMatches(req, alt) {
+			return alt, true
+		}
+	}
+
+	if fixedPath {
+		if cleaned := CleanPath(orig); cleaned != orig {
+			if t.pathMatches(req, cleaned) {
+				return cleaned, true
+			}
+
+			if trailingSlash {
+				if alt := toggleTrailingSlash(cleaned); alt != orig && t.pathMatches(req, alt) {
+					return alt, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// toggleTrailingSlash adds a trailing slash to p if it has none, or removes
+// it (down to "/" at minimum) if it does.
+func toggleTrailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		if trimmed := strings.TrimRight(p, "/"); trimmed != "" {
+			return trimmed
+		}
+		return "/"
+	}
+	return p + "/"
+}
+
+// pathMatches reports whether path matches a route in t for req's method,
+// without mutating req.
+func (t *Trie[T]) pathMatches(req *http.Request, path string) bool {
+	u := *req.URL
+	u.Path = path
+
+	r2 := *req
+	r2.URL = &u
+
+	_, _, _, found := t.lookupNode(&r2)
+	return found
+}
+
+// CleanPath returns the canonical form of p: it collapses repeated
+// slashes, resolves "." and ".." segments, and ensures the result is
+// rooted, without resolving symlinks. It never walks above the root. If p
+// is already clean, CleanPath returns it unmodified without allocating.
+//
+// This is the well-known path-cleaning algorithm used by net/http and
+// julienschmidt/httprouter, adapted to operate on URL paths.
+func CleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	n := len(p)
+	var buf []byte
+
+	r := 1
+	w := 1
+
+	if p[0] != '/' {
+		r = 0
+		buf = make([]byte, n+1)
+		buf[0] = '/'
+	}
+
+	trailing := n > 1 && p[n-1] == '/'
+
+	for r < n {
+		switch {
+		case p[r] == '/':
+			r++
+
+		case p[r] == '.' && r+1 == n:
+			trailing = true
+			r++
+
+		case p[r] == '.' && p[r+1] == '/':
+			r += 2
+
+		case p[r] == '.' && p[r+1] == '.' && (r+2 == n || p[r+2] == '/'):
+			r += 3
+
+			if w > 1 {
+				w--
+
+				if buf == nil {
+					for w > 1 && p[w] != '/' {
+						w--
+					}
+				} else {
+					for w > 1 && buf[w] != '/' {
+						w--
+					}
+				}
+			}
+
+		default:
+			if w > 1 {
+				cleanPathAppend(&buf, p, w, '/')
+				w++
+			}
+
+			for r < n && p[r] != '/' {
+				cleanPathAppend(&buf, p, w, p[r])
+				w++
+				r++
+			}
+		}
+	}
+
+	if trailing && w > 1 {
+		cleanPathAppend(&buf, p, w, '/')
+		w++
+	}
+
+	if buf == nil {
+		return p[:w]
+	}
+	return string(buf[:w])
+}
+
+// cleanPathAppend lazily allocates buf, copying s[:w] into it the first
+// time a byte actually needs to change, then writes c at position w.
+func cleanPathAppend(buf *[]byte, s string, w int, c byte) {
+	b := *buf
+	if b == nil {
+		if s[w] == c {
+			return
+		}
+
+		b = make([]byte, len(s))
+		copy(b, s[:w])
+	}
+	b[w] = c
+	*buf = b
+}
@@ -0,0 +1,92 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nahojer/routes"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		Path string
+		Want string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"/abc", "/abc"},
+		{"/a/b/c", "/a/b/c"},
+		{"//a/b", "/a/b"},
+		{"/a//b", "/a/b"},
+		{"/a/b/", "/a/b/"},
+		{"/a/./b", "/a/b"},
+		{"/a/b/..", "/a"},
+		{"/a/b/../..", "/"},
+		{"/a/b/../../../..", "/"},
+		{"a/b", "/a/b"},
+		{"/./", "/"},
+	}
+	for _, tt := range tests {
+		if got := routes.CleanPath(tt.Path); got != tt.Want {
+			t.Errorf("CleanPath(%q) = %q, want %q", tt.Path, got, tt.Want)
+		}
+	}
+}
+
+// Leading, trailing, and repeated slashes are already insignificant to
+// [Trie.Lookup] (see pathSegments), so a route registered with a trailing
+// slash already matches a request without one -- RedirectTrailingSlash finds
+// nothing left to redirect.
+func TestTrie_RedirectPath_TrailingSlash_AlreadyNormalized(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.RedirectTrailingSlash = true
+	rt.Add("GET", "/users/", "users")
+
+	req := httptest.NewRequest("GET", "http://localhost/users", nil)
+	if _, ok := rt.RedirectPath(req); ok {
+		t.Error("expected no redirect candidate since the path already matches")
+	}
+}
+
+func TestTrie_RedirectPath_FixedPath(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.RedirectFixedPath = true
+	rt.Add("GET", "/users/list", "users")
+
+	req := httptest.NewRequest("GET", "http://localhost/admin/../users/list", nil)
+	path, ok := rt.RedirectPath(req)
+	if !ok {
+		t.Fatal("expected a redirect candidate")
+	}
+	if want := "/users/list"; path != want {
+		t.Errorf("got %q, want %q", path, want)
+	}
+}
+
+func TestTrie_RedirectPath_Disabled(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Add("GET", "/users/", "users")
+
+	req := httptest.NewRequest("GET", "http://localhost/users", nil)
+	if _, ok := rt.RedirectPath(req); ok {
+		t.Error("expected no redirect candidate when both flags are disabled")
+	}
+}
+
+func TestTrie_ServeHTTP_RedirectFixedPath(t *testing.T) {
+	rt := routes.NewTrie[http.Handler]()
+	rt.RedirectFixedPath = true
+	rt.Add("GET", "/users/list", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "http://localhost/admin/../users/list", nil)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMovedPermanently {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusMovedPermanently)
+	}
+	if got, want := w.Header().Get("Location"), "/users/list"; got != want {
+		t.Errorf("got Location %q, want %q", got, want)
+	}
+}
@@ -5,6 +5,8 @@ package routes
 
 import (
 	"net/http"
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -19,10 +21,46 @@ type Trie[T any] struct {
 	//
 	// The default ParamFunc consideres a path segment a parameter if it is
 	// prefixed with a colon (":"). The returned parameter name is the path
-	// segment with all leading colons trimmed.
+	// segment with all leading colons trimmed. A trailing regular expression
+	// wrapped in parentheses, e.g. ":id(\\d+)", is stripped from the name; see
+	// [Trie.ParamConstraint].
 	ParamFunc func(pathSegment string) (name string, isParam bool)
 
+	// ParamConstraint reports whether given parameterized path segment (one
+	// for which ParamFunc returned isParam true) carries a regular expression
+	// constraint, and returns its pattern. The pattern is compiled once, at
+	// [Trie.Add] time, and the segment is only matched by [Trie.Lookup] if the
+	// resulting regular expression matches the whole segment.
+	//
+	// The default ParamConstraint looks for a pattern wrapped in trailing
+	// parentheses, e.g. the "\\d+" in ":id(\\d+)".
+	//
+	// ParamConstraint may be left nil to disable regex constraints entirely.
+	ParamConstraint func(pathSegment string) (pattern string, ok bool)
+
 	root *node[T]
+	// names maps a route name, as registered with Named, to its method and
+	// pattern.
+	names map[string]namedRoute
+	// mws holds global middleware registered with Use.
+	mws []Middleware
+
+	// RedirectTrailingSlash, if true, makes [Trie.RedirectPath] retry a
+	// failed lookup with the path's trailing slash toggled, e.g. trying
+	// "/foo/" when "/foo" has no route, or vice versa. Note that
+	// pathSegments already treats leading, trailing, and repeated slashes as
+	// insignificant, so a path with its trailing slash toggled matches
+	// exactly the routes the original path matches: this retry finds no
+	// alternate path, whether alone or combined with RedirectFixedPath.
+	RedirectTrailingSlash bool
+	// RedirectFixedPath, if true, makes [Trie.RedirectPath] retry a failed
+	// lookup against the path's canonical form, as produced by CleanPath:
+	// repeated slashes collapsed and "." / ".." segments resolved.
+	RedirectFixedPath bool
+
+	// hosts holds routes registered with AddHost, keyed internally by a
+	// synthetic path built from the host pattern's labels; see LookupHost.
+	hosts *Trie[T]
 }
 
 // NewTrie constructs a new Trie for storing and looking up route values of type T.
@@ -32,7 +70,19 @@ func NewTrie[T any]() *Trie[T] {
 			if !strings.HasPrefix(pathSegment, ":") {
 				return "", false
 			}
-			return strings.TrimLeft(pathSegment, ":"), true
+			name = strings.TrimLeft(pathSegment, ":")
+			if i := strings.IndexByte(name, '('); i != -1 {
+				name = name[:i]
+			}
+			return name, true
+		},
+		ParamConstraint: func(pathSegment string) (pattern string, ok bool) {
+			name := strings.TrimLeft(pathSegment, ":")
+			i := strings.IndexByte(name, '(')
+			if i == -1 || !strings.HasSuffix(name, ")") {
+				return "", false
+			}
+			return name[i+1 : len(name)-1], true
 		},
 		root: &node[T]{},
 	}
@@ -47,8 +97,19 @@ func NewTrie[T any]() *Trie[T] {
 // but a part of the path matches a prefix route, then the prefix value will
 // be returned by [Lookup].
 //
-// See [Trie.ParamFunc] for the syntax of path parameters in a pattern.
+// See [Trie.ParamFunc] for the syntax of path parameters in a pattern, and
+// [Trie.ParamConstraint] for constraining a parameter with a regular
+// expression. Add panics if a parameter's constraint is not a valid regular
+// expression.
 func (t *Trie[T]) Add(method, pattern string, value T) {
+	t.addRoute(method, pattern, value, nil, nil)
+}
+
+// addRoute is the shared implementation behind Add, [Group.Add], and
+// AddMatched. mws is the middleware stack, if any, to associate with this
+// route for use by ServeHTTP. matchers, if any, are additional predicates
+// the request must satisfy for Lookup to return this route.
+func (t *Trie[T]) addRoute(method, pattern string, value T, mws []Middleware, matchers []Matcher) {
 	method = strings.ToUpper(method)
 
 	segs := pathSegments(strings.TrimRight(pattern, "..."))
@@ -66,8 +127,14 @@ func (t *Trie[T]) Add(method, pattern string, value T) {
 		}
 
 		var params []string
+		var re *regexp.Regexp
 		if name, isParam := t.ParamFunc(seg); isParam {
 			params = append(params, name)
+			if t.ParamConstraint != nil {
+				if pattern, ok := t.ParamConstraint(seg); ok {
+					re = regexp.MustCompile("^(?:" + pattern + ")$")
+				}
+			}
 		}
 
 		if len(params) > 0 {
@@ -76,11 +143,20 @@ func (t *Trie[T]) Add(method, pattern string, value T) {
 			if child, found := curr.children[key]; found {
 				curr = child
 				curr.params = append(curr.params, params...)
+				if re != nil {
+					if curr.paramRegex == nil {
+						curr.paramRegex = make(map[string]*regexp.Regexp)
+					}
+					curr.paramRegex[method] = re
+				}
 				continue
 			}
 		}
 
 		toAdd := node[T]{params: params}
+		if re != nil {
+			toAdd.paramRegex = map[string]*regexp.Regexp{method: re}
+		}
 		curr.children[key] = &toAdd
 		curr = &toAdd
 	}
@@ -89,28 +165,60 @@ func (t *Trie[T]) Add(method, pattern string, value T) {
 		curr.values = make(map[string]T)
 	}
 	curr.values[method] = value
-	curr.prefix = strings.HasSuffix(pattern, "...")
+
+	if mws != nil {
+		if curr.middlewares == nil {
+			curr.middlewares = make(map[string][]Middleware)
+		}
+		curr.middlewares[method] = mws
+	}
+
+	if matchers != nil {
+		if curr.matchers == nil {
+			curr.matchers = make(map[string][]Matcher)
+		}
+		curr.matchers[method] = matchers
+	}
+
+	if strings.HasSuffix(pattern, "...") {
+		if curr.prefixMethods == nil {
+			curr.prefixMethods = make(map[string]bool)
+		}
+		curr.prefixMethods[method] = true
+	} else if curr.prefixMethods != nil {
+		delete(curr.prefixMethods, method)
+	}
 }
 
 // Lookup searches for the route value associated with given HTTP request.
 func (t *Trie[T]) Lookup(req *http.Request) (value T, params map[string]string, found bool) {
-	var zero T
+	n, method, params, found := t.lookupNode(req)
+	if !found {
+		var zero T
+		return zero, nil, false
+	}
+	return n.values[method], params, true
+}
 
-	method := strings.ToUpper(req.Method)
+// lookupNode is the shared implementation behind Lookup and the middleware
+// resolution used by ServeHTTP. It returns the node holding the matched
+// value, so callers can also reach per-route metadata such as middlewares.
+func (t *Trie[T]) lookupNode(req *http.Request) (n *node[T], method string, params map[string]string, found bool) {
+	method = strings.ToUpper(req.Method)
 
 	segs := pathSegments(req.URL.Path)
 
 	curr := t.root
 	var (
 		prefixMatch bool
-		prefixValue T
+		prefixNode  *node[T]
 	)
 	params = make(map[string]string)
 	for _, seg := range segs {
-		if curr.prefix {
-			if value, ok := curr.values[method]; ok {
+		if curr.prefixMethods[method] {
+			if _, ok := curr.values[method]; ok && matchersAllow(curr.matchers[method], req) {
 				prefixMatch = true
-				prefixValue = value
+				prefixNode = curr
 			}
 		}
 
@@ -120,37 +228,158 @@ func (t *Trie[T]) Lookup(req *http.Request) (value T, params map[string]string,
 		}
 
 		if next, found := curr.children[paramKey]; found {
-			curr = next
-			for _, name := range curr.params {
-				params[name] = seg
+			if re := next.paramRegex[method]; re == nil || re.MatchString(seg) {
+				curr = next
+				for _, name := range curr.params {
+					params[name] = seg
+				}
+				continue
 			}
-			continue
 		}
 
 		if prefixMatch {
-			break
+			// curr did not consume this segment, so it no longer represents
+			// the full request path; only the recorded prefix fallback is a
+			// valid match.
+			return prefixNode, method, params, true
 		}
 
-		return zero, nil, false
+		return nil, method, nil, false
 	}
 
-	if value, ok := curr.values[method]; ok {
-		return value, params, true
+	if _, ok := curr.values[method]; ok && matchersAllow(curr.matchers[method], req) {
+		return curr, method, params, true
 	}
 
 	if prefixMatch {
-		return prefixValue, params, true
+		return prefixNode, method, params, true
+	}
+
+	return nil, method, nil, false
+}
+
+// matchersAllow reports whether req satisfies every matcher in matchers.
+func matchersAllow(matchers []Matcher, req *http.Request) bool {
+	for _, m := range matchers {
+		if !m.Match(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowedMethods walks the trie for the path component of req's URL and
+// reports the HTTP methods registered there, ignoring req's own method. A
+// method is excluded if its [Trie.ParamConstraint] regular expression
+// rejects a path segment, or if matchers registered with AddMatched reject
+// req, the same as [Trie.Lookup] would exclude it. It returns ok false if
+// no registered route's path matches at all, letting callers distinguish
+// "no route" (404) from "wrong method" (405) for a request that
+// [Trie.Lookup] failed to find.
+func (t *Trie[T]) AllowedMethods(req *http.Request) (methods []string, ok bool) {
+	segs := pathSegments(req.URL.Path)
+
+	curr := t.root
+	var prefixNode *node[T]
+	excluded := make(map[string]bool)
+	for _, seg := range segs {
+		if len(curr.prefixMethods) > 0 && len(curr.values) > 0 {
+			prefixNode = curr
+		}
+
+		if next, found := curr.children[seg]; found {
+			curr = next
+			continue
+		}
+
+		if next, found := curr.children[paramKey]; found {
+			// Unlike lookupNode, this walk isn't scoped to a single HTTP
+			// method, so a segment that only satisfies some methods'
+			// constraints still lets the walk continue; methodsOf excludes
+			// the methods left unsatisfied below.
+			matched := len(next.paramRegex) == 0
+			for m, re := range next.paramRegex {
+				if re.MatchString(seg) {
+					matched = true
+				} else {
+					excluded[m] = true
+				}
+			}
+			if matched {
+				curr = next
+				continue
+			}
+		}
+
+		if prefixNode != nil {
+			if ms := methodsOf(prefixNode, req, excluded, true); len(ms) > 0 {
+				return ms, true
+			}
+		}
+
+		return nil, false
 	}
 
-	return zero, nil, false
+	if len(curr.values) > 0 {
+		if ms := methodsOf(curr, req, excluded, false); len(ms) > 0 {
+			return ms, true
+		}
+	}
+
+	if prefixNode != nil {
+		if ms := methodsOf(prefixNode, req, excluded, true); len(ms) > 0 {
+			return ms, true
+		}
+	}
+
+	return nil, false
+}
+
+// methodsOf returns the sorted set of HTTP methods registered on n, minus
+// any in excluded (methods whose [Trie.ParamConstraint] rejected a path
+// segment) and any whose AddMatched matchers reject req. onlyPrefix must be
+// true when n is being reported as a prefix fallback for a request path
+// that goes deeper than n's own registered routes: in that case a method
+// only qualifies if it was registered as a prefix route (trailing "...")
+// on n, since an exact or parameterized route registered on n, sharing the
+// node with an unrelated method's prefix route, doesn't itself extend to
+// paths below n. onlyPrefix is false when n is the exact node the request
+// path resolved to, where every method legitimately applies.
+func methodsOf[T any](n *node[T], req *http.Request, excluded map[string]bool, onlyPrefix bool) []string {
+	methods := make([]string, 0, len(n.values))
+	for m := range n.values {
+		if excluded[m] || !matchersAllow(n.matchers[m], req) {
+			continue
+		}
+		if onlyPrefix && !n.prefixMethods[m] {
+			continue
+		}
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
 }
 
 type node[T any] struct {
 	children map[string]*node[T]
 	params   []string
-	prefix   bool
+	// paramRegex, per HTTP method, constrains the path segment matched by
+	// this parameter node; see [Trie.ParamConstraint]. A method absent from
+	// the map is unconstrained.
+	paramRegex map[string]*regexp.Regexp
+	// prefixMethods marks, per HTTP method, that the route registered for
+	// this node was a prefix pattern (trailing "..."). Tracking this per
+	// method lets a prefix route coexist at the same node as an exact or
+	// parameterized route registered for a different method; see Add.
+	prefixMethods map[string]bool
 	// All routes values accessed by HTTP method.
 	values map[string]T
+	// middlewares holds, per HTTP method, the middleware stack registered
+	// for this route's value via [Trie.Group]; see ServeHTTP.
+	middlewares map[string][]Middleware
+	// matchers holds, per HTTP method, the additional predicates registered
+	// for this route's value via AddMatched.
+	matchers map[string][]Matcher
 }
 
 func pathSegments(p string) []string {
@@ -231,6 +231,200 @@ func TestTrie_CustomParamFunc(t *testing.T) {
 	}
 }
 
+func TestTrie_ParamConstraint(t *testing.T) {
+	rt := routes.NewTrie[string]()
+
+	rt.Add("GET", "/users/:id(\\d+)", "user")
+
+	tests := []struct {
+		Path  string
+		Match bool
+		ID    string
+	}{
+		{"/users/123", true, "123"},
+		{"/users/abc", false, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "http://localhost"+tt.Path, nil)
+
+		gotValue, gotParams, found := rt.Lookup(req)
+		if found != tt.Match {
+			t.Errorf("Lookup(%q) found = %t, want %t", tt.Path, found, tt.Match)
+			continue
+		}
+		if !tt.Match {
+			continue
+		}
+		if gotValue != "user" {
+			t.Errorf("Lookup(%q) value = %q, want %q", tt.Path, gotValue, "user")
+		}
+		if gotParams["id"] != tt.ID {
+			t.Errorf("Lookup(%q) param id = %q, want %q", tt.Path, gotParams["id"], tt.ID)
+		}
+	}
+}
+
+func TestTrie_ParamConstraint_DisabledByNil(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.ParamConstraint = nil
+
+	rt.Add("GET", "/users/:id(\\d+)", "user")
+
+	req := httptest.NewRequest("GET", "http://localhost/users/abc", nil)
+	_, gotParams, found := rt.Lookup(req)
+	if !found {
+		t.Fatal("Should be able to find value when ParamConstraint is nil")
+	}
+	if gotParams["id"] != "abc" {
+		t.Errorf("got param id %q, want %q", gotParams["id"], "abc")
+	}
+}
+
+// Two methods sharing a parameterized node must keep independent
+// constraints: registering a regex for POST must not affect what GET
+// already had, and vice versa.
+func TestTrie_ParamConstraint_PerMethod(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Add("GET", "/users/:id(\\d+)", "get-numeric")
+	rt.Add("POST", "/users/:id([a-z]+)", "post-alpha")
+
+	req := httptest.NewRequest("GET", "http://localhost/users/abc", nil)
+	if _, _, found := rt.Lookup(req); found {
+		t.Error("GET with a non-numeric id should not match a \\d+ constraint")
+	}
+
+	req = httptest.NewRequest("GET", "http://localhost/users/123", nil)
+	gotValue, _, found := rt.Lookup(req)
+	if !found || gotValue != "get-numeric" {
+		t.Errorf("Lookup(GET, /users/123) = %q, %t; want %q, true", gotValue, found, "get-numeric")
+	}
+
+	req = httptest.NewRequest("POST", "http://localhost/users/abc", nil)
+	gotValue, _, found = rt.Lookup(req)
+	if !found || gotValue != "post-alpha" {
+		t.Errorf("Lookup(POST, /users/abc) = %q, %t; want %q, true", gotValue, found, "post-alpha")
+	}
+
+	req = httptest.NewRequest("POST", "http://localhost/users/123", nil)
+	if _, _, found := rt.Lookup(req); found {
+		t.Error("POST with a numeric id should not match a [a-z]+ constraint")
+	}
+}
+
+func TestTrie_CatchAllCoexistsWithSiblings(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Add("GET", "/...", "catchall")
+	rt.Add("GET", "/normal", "normal")
+	rt.Add("GET", "/users/...", "users-catchall")
+	rt.Add("GET", "/users/new", "users-new")
+	rt.Add("GET", "/users/:id", "users-id")
+
+	tests := []struct {
+		Path      string
+		WantValue string
+	}{
+		{"/normal", "normal"},
+		{"/other", "catchall"},
+		{"/users/new", "users-new"},
+		{"/users/123", "users-id"},
+		{"/users/123/extra", "users-catchall"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "http://localhost"+tt.Path, nil)
+		gotValue, _, found := rt.Lookup(req)
+		if !found || gotValue != tt.WantValue {
+			t.Errorf("Lookup(%q) = %q, %t; want %q, true", tt.Path, gotValue, found, tt.WantValue)
+		}
+	}
+}
+
+// A prefix route and an exact/parameterized route can land on the same
+// trie node when one pattern's trailing "..." is stripped down to the
+// other's literal pattern, e.g. "/users/:id" and "/users/:id/...". Each
+// HTTP method must keep its own prefix-ness so that registering a
+// catch-all for one method doesn't leak into another method sharing the
+// node.
+func TestTrie_CatchAllPrefixIsPerMethod(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Add("GET", "/users/:id", "get-id")
+	rt.Add("POST", "/users/:id/...", "post-catchall")
+
+	req := httptest.NewRequest("GET", "http://localhost/users/123/extra", nil)
+	if _, _, found := rt.Lookup(req); found {
+		t.Error("GET should not fall back to a catch-all registered only for POST")
+	}
+
+	req = httptest.NewRequest("POST", "http://localhost/users/123/extra", nil)
+	gotValue, _, found := rt.Lookup(req)
+	if !found || gotValue != "post-catchall" {
+		t.Errorf("Lookup(POST) = %q, %t; want %q, true", gotValue, found, "post-catchall")
+	}
+
+	req = httptest.NewRequest("GET", "http://localhost/users/123", nil)
+	gotValue, _, found = rt.Lookup(req)
+	if !found || gotValue != "get-id" {
+		t.Errorf("Lookup(GET) = %q, %t; want %q, true", gotValue, found, "get-id")
+	}
+}
+
+// When a request path goes deeper than any registered node and
+// AllowedMethods falls back to a prefix node, only the methods actually
+// registered as prefix routes there qualify. A sibling method that landed
+// on the same node with a non-prefix route doesn't match deeper paths and
+// must not be advertised.
+func TestTrie_AllowedMethods_PrefixFallbackIsPerMethod(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Add("GET", "/users/...", "users-catchall")
+	rt.Add("POST", "/users", "users-create")
+
+	req := httptest.NewRequest("DELETE", "http://localhost/users/extra/garbage", nil)
+	methods, ok := rt.AllowedMethods(req)
+	if !ok {
+		t.Fatal("expected AllowedMethods to report the path exists")
+	}
+	if len(methods) != 1 || methods[0] != "GET" {
+		t.Errorf("got methods %v, want [GET] (POST is not a prefix route here)", methods)
+	}
+
+	req = httptest.NewRequest("DELETE", "http://localhost/users", nil)
+	methods, ok = rt.AllowedMethods(req)
+	if !ok {
+		t.Fatal("expected AllowedMethods to report the path exists")
+	}
+	want := []string{"GET", "POST"}
+	if len(methods) != len(want) || methods[0] != want[0] || methods[1] != want[1] {
+		t.Errorf("got methods %v, want %v (exact match: both methods apply)", methods, want)
+	}
+}
+
+func TestTrie_AllowedMethods(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Add("GET", "/widgets/:id", "get")
+	rt.Add("DELETE", "/widgets/:id", "delete")
+
+	req := httptest.NewRequest("POST", "http://localhost/widgets/42", nil)
+	methods, ok := rt.AllowedMethods(req)
+	if !ok {
+		t.Fatal("expected AllowedMethods to report the path exists")
+	}
+
+	want := []string{"DELETE", "GET"}
+	if len(methods) != len(want) {
+		t.Fatalf("got methods %v, want %v", methods, want)
+	}
+	for i := range want {
+		if methods[i] != want[i] {
+			t.Errorf("got methods %v, want %v", methods, want)
+			break
+		}
+	}
+
+	req = httptest.NewRequest("GET", "http://localhost/missing", nil)
+	if _, ok := rt.AllowedMethods(req); ok {
+		t.Error("expected AllowedMethods to report no match for an unregistered path")
+	}
+}
+
 // isSubset reports whether sub is a subset of m.
 func isSubset[K, V comparable](m, sub map[K]V) bool {
 	if len(sub) > len(m) {
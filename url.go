@@ -0,0 +1,108 @@
+package routes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// namedRoute records the method and pattern registered under a name by
+// [Trie.Named], so [Trie.URL] can walk back to the same trie nodes [Trie.Add]
+// created and reuse their already-compiled [Trie.ParamConstraint] regular
+// expressions.
+type namedRoute struct {
+	method  string
+	pattern string
+}
+
+// Named inserts a route value like [Trie.Add], additionally registering
+// pattern under name so its concrete URL can later be rebuilt with
+// [Trie.URL]. Named panics if name is already registered for a different
+// pattern.
+func (t *Trie[T]) Named(method, pattern, name string, value T) {
+	t.Add(method, pattern, value)
+	method = strings.ToUpper(method)
+
+	if existing, ok := t.names[name]; ok && existing.pattern != pattern {
+		panic(fmt.Sprintf("routes: name %q already registered for pattern %q, got %q", name, existing.pattern, pattern))
+	}
+
+	if t.names == nil {
+		t.names = make(map[string]namedRoute)
+	}
+	t.names[name] = namedRoute{method: method, pattern: pattern}
+}
+
+// URL reconstructs the concrete path for the route registered under name
+// with [Trie.Named], substituting params into the pattern's parameterized
+// segments. If the named pattern is a prefix route (trailing "..."), an
+// optional trailing path may be supplied via the "..." key in params.
+//
+// Each param value is written into its own path segment and must not
+// contain a "/"; this includes values URL itself does not otherwise
+// validate, so building a URL from untrusted input (e.g. a request body)
+// cannot widen the resulting path beyond the named route's own segment
+// structure. The "..." rest value is the one exception: it is appended
+// verbatim (after trimming leading/trailing slashes) since the named
+// pattern itself declares that segment open-ended.
+//
+// URL returns an error if name is not registered, a required param is
+// missing, a param value contains "/", or a param value does not satisfy
+// the segment's [Trie.ParamConstraint].
+func (t *Trie[T]) URL(name string, params map[string]string) (string, error) {
+	route, ok := t.names[name]
+	if !ok {
+		return "", fmt.Errorf("routes: no route named %q", name)
+	}
+
+	isPrefix := strings.HasSuffix(route.pattern, "...")
+	segs := pathSegments(strings.TrimRight(route.pattern, "."))
+
+	var b strings.Builder
+	curr := t.root
+	for _, seg := range segs {
+		pname, isParam := t.ParamFunc(seg)
+		if !isParam {
+			b.WriteByte('/')
+			b.WriteString(seg)
+			if curr != nil {
+				curr = curr.children[seg]
+			}
+			continue
+		}
+
+		value, ok := params[pname]
+		if !ok {
+			return "", fmt.Errorf("routes: missing param %q for route %q", pname, name)
+		}
+		if strings.Contains(value, "/") {
+			return "", fmt.Errorf("routes: param %q value %q must not contain %q", pname, value, "/")
+		}
+
+		var paramNode *node[T]
+		if curr != nil {
+			paramNode = curr.children[paramKey]
+		}
+		if paramNode != nil {
+			if re := paramNode.paramRegex[route.method]; re != nil && !re.MatchString(value) {
+				return "", fmt.Errorf("routes: param %q value %q does not satisfy constraint for route %q", pname, value, name)
+			}
+		}
+		curr = paramNode
+
+		b.WriteByte('/')
+		b.WriteString(value)
+	}
+
+	if isPrefix {
+		if rest := strings.Trim(params["..."], "/"); rest != "" {
+			b.WriteByte('/')
+			b.WriteString(rest)
+		}
+	}
+
+	if b.Len() == 0 {
+		return "/", nil
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,125 @@
+package routes_test
+
+import (
+	"testing"
+
+	"github.com/nahojer/routes"
+)
+
+func TestTrie_NamedAndURL(t *testing.T) {
+	rt := routes.NewTrie[string]()
+
+	rt.Named("GET", "/path-params/:era/:group/:member", "member", "value")
+	rt.Named("GET", "/path-params-prefix/:era/:group/...", "prefix", "value")
+
+	tests := []struct {
+		Name    string
+		Params  map[string]string
+		WantURL string
+		WantErr bool
+	}{
+		{
+			"member",
+			map[string]string{"era": "60s", "group": "beatles", "member": "lennon"},
+			"/path-params/60s/beatles/lennon",
+			false,
+		},
+		{
+			"member",
+			map[string]string{"era": "60s", "group": "beatles"},
+			"",
+			true,
+		},
+		{
+			"prefix",
+			map[string]string{"era": "60s", "group": "beatles", "...": "award-winners/lennon"},
+			"/path-params-prefix/60s/beatles/award-winners/lennon",
+			false,
+		},
+		{
+			"prefix",
+			map[string]string{"era": "60s", "group": "beatles"},
+			"/path-params-prefix/60s/beatles",
+			false,
+		},
+		{
+			"missing",
+			nil,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		gotURL, err := rt.URL(tt.Name, tt.Params)
+		if (err != nil) != tt.WantErr {
+			t.Errorf("URL(%q, %+v) error = %v, wantErr %t", tt.Name, tt.Params, err, tt.WantErr)
+			continue
+		}
+		if err == nil && gotURL != tt.WantURL {
+			t.Errorf("URL(%q, %+v) = %q, want %q", tt.Name, tt.Params, gotURL, tt.WantURL)
+		}
+	}
+}
+
+func TestTrie_URL_ParamConstraint(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Named("GET", "/users/:id(\\d+)", "user", "value")
+
+	if _, err := rt.URL("user", map[string]string{"id": "abc"}); err == nil {
+		t.Error("expected error for param value that fails constraint")
+	}
+
+	gotURL, err := rt.URL("user", map[string]string{"id": "123"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "/users/123"; gotURL != want {
+		t.Errorf("got %q, want %q", gotURL, want)
+	}
+}
+
+// A param value must not be able to smuggle extra path segments into the
+// URL it's substituted into, e.g. to escape the named route's subtree.
+func TestTrie_URL_RejectsSlashInParamValue(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Named("GET", "/users/:id", "user", "value")
+
+	if _, err := rt.URL("user", map[string]string{"id": "../../etc/passwd"}); err == nil {
+		t.Error("expected error for a param value containing a path separator")
+	}
+}
+
+// Two methods sharing a parameterized node can carry different
+// [Trie.ParamConstraint] regular expressions; URL must validate against the
+// constraint registered for the named route's own method, using the
+// regular expression already compiled at Add time rather than recompiling
+// the pattern string.
+func TestTrie_URL_ParamConstraint_PerMethod(t *testing.T) {
+	rt := routes.NewTrie[string]()
+	rt.Add("POST", "/users/:id([a-z]+)", "post-alpha")
+	rt.Named("GET", "/users/:id(\\d+)", "user", "get-numeric")
+
+	if _, err := rt.URL("user", map[string]string{"id": "abc"}); err == nil {
+		t.Error("expected error: \"abc\" fails the GET route's \\d+ constraint")
+	}
+
+	gotURL, err := rt.URL("user", map[string]string{"id": "123"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "/users/123"; gotURL != want {
+		t.Errorf("got %q, want %q", gotURL, want)
+	}
+}
+
+func TestTrie_Named_ConflictingPatternPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when re-registering a name with a different pattern")
+		}
+	}()
+
+	rt := routes.NewTrie[string]()
+	rt.Named("GET", "/a/:id", "thing", "value")
+	rt.Named("GET", "/b/:id", "thing", "value")
+}